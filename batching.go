@@ -0,0 +1,239 @@
+package channelio
+
+import (
+	"fmt"
+	"sync"
+	"time"
+)
+
+// BatchingEmitter returns an Emitter that coalesces successive Emit calls
+// into a single downstream []any emission on inner, once either maxSize
+// values have been accumulated or maxDelay has elapsed since the first of
+// the pending batch.
+//
+// Emit itself never blocks on maxDelay: the time-based flush is driven by an
+// internal timer. If a flush triggered by that timer fails, the error is
+// returned by the next call to Emit.
+func BatchingEmitter(inner Emitter, maxSize int, maxDelay time.Duration) Emitter {
+	return &batchingEmitter{
+		inner:    inner,
+		maxSize:  maxSize,
+		maxDelay: maxDelay,
+	}
+}
+
+type batchingEmitter struct {
+	inner    Emitter
+	maxSize  int
+	maxDelay time.Duration
+
+	mu    sync.Mutex
+	batch []any
+	timer *time.Timer
+	err   error
+
+	// flushMu serializes the actual calls to inner.Emit across the
+	// size-triggered flush (driven by the caller's own goroutine, in Emit)
+	// and the time-triggered flush (driven by the timer's own goroutine, in
+	// flushTimeout). Batches are handed off to flush in the order they are
+	// cut from b.batch under mu, so holding flushMu across inner.Emit keeps
+	// that same order on the wire instead of letting the two goroutines race
+	// past each other.
+	flushMu sync.Mutex
+}
+
+func (b *batchingEmitter) Emit(value any) error {
+	b.mu.Lock()
+
+	if b.err != nil {
+		err := b.err
+		b.mu.Unlock()
+
+		return err
+	}
+
+	b.batch = append(b.batch, value)
+
+	if b.timer == nil {
+		b.timer = time.AfterFunc(b.maxDelay, b.flushTimeout)
+	}
+
+	var batch []any
+
+	if len(b.batch) >= b.maxSize {
+		batch = b.batch
+		b.batch = nil
+		b.timer.Stop()
+		b.timer = nil
+	}
+
+	b.mu.Unlock()
+
+	if batch != nil {
+		return b.flush(batch)
+	}
+
+	return nil
+}
+
+func (b *batchingEmitter) flushTimeout() {
+	b.mu.Lock()
+	batch := b.batch
+	b.batch = nil
+	b.timer = nil
+	b.mu.Unlock()
+
+	if len(batch) > 0 {
+		b.flush(batch)
+	}
+}
+
+func (b *batchingEmitter) flush(batch []any) error {
+	b.flushMu.Lock()
+	defer b.flushMu.Unlock()
+
+	if err := b.inner.Emit(batch); err != nil {
+		b.mu.Lock()
+		b.err = err
+		b.mu.Unlock()
+
+		return err
+	}
+
+	return nil
+}
+
+// UnbatchingReceiver returns a Receiver that flattens the []any batches
+// produced by a BatchingEmitter back into the individual values they were
+// made of.
+func UnbatchingReceiver(inner Receiver) Receiver {
+	return &unbatchingReceiver{receiver: inner}
+}
+
+type unbatchingReceiver struct {
+	receiver Receiver
+	pending  []any
+}
+
+func (u *unbatchingReceiver) Receive() (any, error) {
+	for len(u.pending) == 0 {
+		value, err := u.receiver.Receive()
+
+		if err != nil {
+			return nil, err
+		}
+
+		batch, ok := value.([]any)
+
+		if !ok {
+			return nil, fmt.Errorf("channelio: expected a batch ([]any), got %T", value)
+		}
+
+		u.pending = batch
+	}
+
+	value := u.pending[0]
+	u.pending = u.pending[1:]
+
+	return value, nil
+}
+
+// OverflowPolicy controls what BufferedReceiver does when its internal
+// buffer is full and a new value comes in from the wrapped Receiver.
+type OverflowPolicy int
+
+const (
+	// Block makes BufferedReceiver wait for room to free up in the buffer
+	// before storing the value it has just read from the wrapped Receiver;
+	// no further value is read from the wrapped Receiver while it waits.
+	Block OverflowPolicy = iota
+
+	// DropOldest makes BufferedReceiver discard the oldest buffered value
+	// to make room for the incoming one.
+	DropOldest
+
+	// DropNewest makes BufferedReceiver discard the incoming value when the
+	// buffer is full, keeping what it already has buffered.
+	DropNewest
+)
+
+// BufferedReceiver returns a Receiver that eagerly drains inner into an
+// internal buffer of up to size values, so that Receive never has to wait
+// for inner directly. policy controls what happens once that buffer fills
+// up.
+func BufferedReceiver(inner Receiver, size int, policy OverflowPolicy) Receiver {
+	b := &bufferedReceiver{
+		size:   size,
+		policy: policy,
+	}
+	b.cond = sync.NewCond(&b.mu)
+
+	go b.run(inner)
+
+	return b
+}
+
+type bufferedReceiver struct {
+	size   int
+	policy OverflowPolicy
+
+	mu     sync.Mutex
+	cond   *sync.Cond
+	buffer []any
+	err    error
+	closed bool
+}
+
+func (b *bufferedReceiver) run(inner Receiver) {
+	for {
+		value, err := inner.Receive()
+
+		b.mu.Lock()
+
+		if err != nil {
+			b.err = err
+			b.closed = true
+			b.cond.Broadcast()
+			b.mu.Unlock()
+
+			return
+		}
+
+		if len(b.buffer) >= b.size {
+			switch b.policy {
+			case DropOldest:
+				b.buffer = b.buffer[1:]
+			case DropNewest:
+				b.mu.Unlock()
+				continue
+			default:
+				for len(b.buffer) >= b.size && !b.closed {
+					b.cond.Wait()
+				}
+			}
+		}
+
+		b.buffer = append(b.buffer, value)
+		b.cond.Broadcast()
+		b.mu.Unlock()
+	}
+}
+
+func (b *bufferedReceiver) Receive() (any, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	for len(b.buffer) == 0 && !b.closed {
+		b.cond.Wait()
+	}
+
+	if len(b.buffer) > 0 {
+		value := b.buffer[0]
+		b.buffer = b.buffer[1:]
+		b.cond.Broadcast()
+
+		return value, nil
+	}
+
+	return nil, b.err
+}
@@ -0,0 +1,357 @@
+package channelio
+
+import (
+	"io"
+	"sync"
+	"testing"
+	"time"
+)
+
+// captureEmitter is an Emitter that records every value it receives.
+type captureEmitter struct {
+	mu     sync.Mutex
+	values []any
+}
+
+func (c *captureEmitter) Emit(value any) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.values = append(c.values, value)
+
+	return nil
+}
+
+func (c *captureEmitter) snapshot() []any {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return append([]any(nil), c.values...)
+}
+
+// chanReceiver is a Receiver fed by a channel, returning io.EOF once it is
+// closed.
+type chanReceiver struct {
+	values chan any
+}
+
+func (c *chanReceiver) Receive() (any, error) {
+	value, ok := <-c.values
+
+	if !ok {
+		return nil, io.EOF
+	}
+
+	return value, nil
+}
+
+func TestBatchingEmitterFlushesOnMaxSize(t *testing.T) {
+	inner := &captureEmitter{}
+	emitter := BatchingEmitter(inner, 2, time.Hour)
+
+	if err := emitter.Emit(1); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if got := inner.snapshot(); len(got) != 0 {
+		t.Fatalf("expected no flush yet, got %v", got)
+	}
+
+	if err := emitter.Emit(2); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	got := inner.snapshot()
+
+	if len(got) != 1 {
+		t.Fatalf("expected a single flushed batch, got %v", got)
+	}
+
+	batch, ok := got[0].([]any)
+
+	if !ok || len(batch) != 2 || batch[0] != 1 || batch[1] != 2 {
+		t.Fatalf("expected batch [1 2], got %v", got[0])
+	}
+}
+
+func TestBatchingEmitterFlushesOnMaxDelay(t *testing.T) {
+	inner := &captureEmitter{}
+	emitter := BatchingEmitter(inner, 10, 10*time.Millisecond)
+
+	if err := emitter.Emit("only"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	deadline := time.Now().Add(time.Second)
+
+	for time.Now().Before(deadline) {
+		if len(inner.snapshot()) > 0 {
+			break
+		}
+
+		time.Sleep(time.Millisecond)
+	}
+
+	got := inner.snapshot()
+
+	if len(got) != 1 {
+		t.Fatalf("expected the pending batch to flush after maxDelay, got %v", got)
+	}
+
+	batch, ok := got[0].([]any)
+
+	if !ok || len(batch) != 1 || batch[0] != "only" {
+		t.Fatalf("expected batch [only], got %v", got[0])
+	}
+}
+
+// emitterFunc adapts a plain function to the Emitter interface.
+type emitterFunc func(value any) error
+
+func (f emitterFunc) Emit(value any) error {
+	return f(value)
+}
+
+func TestBatchingEmitterSerializesFlushes(t *testing.T) {
+	entered := make(chan []any, 2)
+	proceed := make(chan struct{})
+
+	inner := emitterFunc(func(value any) error {
+		entered <- value.([]any)
+		<-proceed
+
+		return nil
+	})
+
+	emitter := BatchingEmitter(inner, 10, 5*time.Millisecond)
+
+	if err := emitter.Emit(1); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// Wait for the timer-triggered flush to enter inner.Emit; it then blocks
+	// there until proceed is closed.
+	select {
+	case first := <-entered:
+		if len(first) != 1 || first[0] != 1 {
+			t.Fatalf("expected [1], got %v", first)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("timed out waiting for the timer-triggered flush to start")
+	}
+
+	// While the timer-triggered flush is still inside inner.Emit, fill the
+	// batch to maxSize from another goroutine to trigger a size-triggered
+	// flush concurrently.
+	done := make(chan error, 1)
+
+	go func() {
+		for i := 0; i < 10; i++ {
+			if err := emitter.Emit(i + 2); err != nil {
+				done <- err
+				return
+			}
+		}
+
+		done <- nil
+	}()
+
+	// The size-triggered flush must wait for the timer-triggered one to
+	// finish before it gets to call inner.Emit itself: without a flushMu
+	// serializing the two, the size-triggered flush could call inner.Emit
+	// for the newer batch before the older one, reordering values on the
+	// wire.
+	select {
+	case <-entered:
+		t.Fatal("expected the size-triggered flush to wait for the timer-triggered one")
+	case <-time.After(20 * time.Millisecond):
+	}
+
+	close(proceed)
+
+	select {
+	case second := <-entered:
+		if len(second) != 10 {
+			t.Fatalf("expected a batch of 10, got %v", second)
+		}
+
+		for i, value := range second {
+			if value != i+2 {
+				t.Fatalf("expected the batch in submission order, got %v", second)
+			}
+		}
+	case <-time.After(time.Second):
+		t.Fatal("timed out waiting for the size-triggered flush")
+	}
+
+	if err := <-done; err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestUnbatchingReceiverFlattensBatches(t *testing.T) {
+	receiver := UnbatchingReceiver(&sliceReceiver{values: []interface{}{
+		[]any{1, 2},
+		[]any{3},
+	}})
+
+	for _, want := range []any{1, 2, 3} {
+		got, err := receiver.Receive()
+
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if got != want {
+			t.Fatalf("expected %v, got %v", want, got)
+		}
+	}
+
+	if _, err := receiver.Receive(); err != io.EOF {
+		t.Fatalf("expected io.EOF once exhausted, got %v", err)
+	}
+}
+
+func TestUnbatchingReceiverRejectsNonBatchValues(t *testing.T) {
+	receiver := UnbatchingReceiver(&sliceReceiver{values: []interface{}{42}})
+
+	if _, err := receiver.Receive(); err == nil {
+		t.Fatal("expected an error, got nil")
+	}
+}
+
+func TestBufferedReceiverBlockPolicyPreservesOrder(t *testing.T) {
+	values := make(chan any)
+	receiver := BufferedReceiver(&chanReceiver{values: values}, 1, Block)
+
+	go func() {
+		values <- 1
+		values <- 2
+		values <- 3
+	}()
+
+	for _, want := range []any{1, 2, 3} {
+		got, err := receiver.Receive()
+
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if got != want {
+			t.Fatalf("expected %v, got %v", want, got)
+		}
+	}
+}
+
+// waitForBuffered polls r's internal buffer until its contents equal want,
+// letting the background goroutine started by BufferedReceiver finish
+// applying its overflow policy before the test reads. Polling for the exact
+// final contents, rather than just a length, avoids racing against the
+// buffer's transient states on the way there.
+func waitForBuffered(t *testing.T, r Receiver, want []any) {
+	t.Helper()
+
+	b := r.(*bufferedReceiver)
+	deadline := time.Now().Add(time.Second)
+
+	equal := func(a, b []any) bool {
+		if len(a) != len(b) {
+			return false
+		}
+
+		for i := range a {
+			if a[i] != b[i] {
+				return false
+			}
+		}
+
+		return true
+	}
+
+	for time.Now().Before(deadline) {
+		b.mu.Lock()
+		got := append([]any(nil), b.buffer...)
+		b.mu.Unlock()
+
+		if equal(got, want) {
+			return
+		}
+
+		time.Sleep(time.Millisecond)
+	}
+
+	t.Fatalf("timed out waiting for the buffer to become %v", want)
+}
+
+func TestBufferedReceiverDropOldestDiscardsOldestOnOverflow(t *testing.T) {
+	values := make(chan any)
+	receiver := BufferedReceiver(&chanReceiver{values: values}, 2, DropOldest)
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+
+	go func() {
+		defer wg.Done()
+
+		values <- 1
+		values <- 2
+		values <- 3
+	}()
+
+	wg.Wait()
+	waitForBuffered(t, receiver, []any{2, 3})
+
+	for _, want := range []any{2, 3} {
+		got, err := receiver.Receive()
+
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if got != want {
+			t.Fatalf("expected %v, got %v", want, got)
+		}
+	}
+}
+
+func TestBufferedReceiverDropNewestDiscardsIncomingOnOverflow(t *testing.T) {
+	values := make(chan any)
+	receiver := BufferedReceiver(&chanReceiver{values: values}, 2, DropNewest)
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+
+	go func() {
+		defer wg.Done()
+
+		values <- 1
+		values <- 2
+		values <- 3
+	}()
+
+	wg.Wait()
+	waitForBuffered(t, receiver, []any{1, 2})
+
+	for _, want := range []any{1, 2} {
+		got, err := receiver.Receive()
+
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if got != want {
+			t.Fatalf("expected %v, got %v", want, got)
+		}
+	}
+}
+
+func TestBufferedReceiverPropagatesUnderlyingError(t *testing.T) {
+	values := make(chan any)
+	close(values)
+
+	receiver := BufferedReceiver(&chanReceiver{values: values}, 2, Block)
+
+	if _, err := receiver.Receive(); err != io.EOF {
+		t.Fatalf("expected io.EOF, got %v", err)
+	}
+}
@@ -0,0 +1,136 @@
+// Package codec provides concrete channelio Transmitter implementations
+// backed by an io.ReadWriter, parameterized over a pluggable Codec.
+package codec
+
+import (
+	"encoding/gob"
+	"encoding/json"
+	"io"
+
+	"github.com/ereOn/go-channelio"
+)
+
+// Codec abstracts the encoding and decoding of a stream of values onto a
+// byte stream.
+type Codec interface {
+	// NewEncoder returns an Encoder that writes values to w.
+	NewEncoder(w io.Writer) Encoder
+	// NewDecoder returns a Decoder that reads values from r.
+	NewDecoder(r io.Reader) Decoder
+}
+
+// Encoder encodes successive values onto a stream.
+type Encoder interface {
+	// Encode writes value to the underlying stream.
+	Encode(value any) error
+}
+
+// Decoder decodes successive values from a stream.
+type Decoder interface {
+	// Decode reads and returns the next value from the underlying stream.
+	Decode() (any, error)
+}
+
+// NewTransmitter returns a Transmitter that encodes and decodes values onto
+// rw using codec.
+//
+// Per the channelio.RunReceiver contract, it is the caller's responsibility
+// to close rw once the context given to channelio.RunReceiver (or
+// RunTransmitter) expires, so that the in-flight read unblocks.
+func NewTransmitter(rw io.ReadWriter, codec Codec) channelio.Transmitter {
+	return channelio.ComposeTransmitter(
+		emitterAdapter{encoder: codec.NewEncoder(rw)},
+		receiverAdapter{decoder: codec.NewDecoder(rw)},
+	)
+}
+
+type emitterAdapter struct {
+	encoder Encoder
+}
+
+func (e emitterAdapter) Emit(value any) error {
+	return e.encoder.Encode(value)
+}
+
+type receiverAdapter struct {
+	decoder Decoder
+}
+
+func (r receiverAdapter) Receive() (any, error) {
+	return r.decoder.Decode()
+}
+
+// JSONCodec is a Codec that encodes and decodes values as a stream of JSON
+// values. Decoded values follow the usual encoding/json rules for decoding
+// into an empty interface (map[string]any, []any, float64, string, bool, or
+// nil).
+var JSONCodec Codec = jsonCodec{}
+
+type jsonCodec struct{}
+
+func (jsonCodec) NewEncoder(w io.Writer) Encoder {
+	return &jsonEncoder{encoder: json.NewEncoder(w)}
+}
+
+func (jsonCodec) NewDecoder(r io.Reader) Decoder {
+	return &jsonDecoder{decoder: json.NewDecoder(r)}
+}
+
+type jsonEncoder struct {
+	encoder *json.Encoder
+}
+
+func (e *jsonEncoder) Encode(value any) error {
+	return e.encoder.Encode(value)
+}
+
+type jsonDecoder struct {
+	decoder *json.Decoder
+}
+
+func (d *jsonDecoder) Decode() (any, error) {
+	var value any
+
+	if err := d.decoder.Decode(&value); err != nil {
+		return nil, err
+	}
+
+	return value, nil
+}
+
+// GobCodec is a Codec that encodes and decodes values as a stream of gob
+// values. The dynamic type of every encoded value must have been registered
+// with gob.Register beforehand.
+var GobCodec Codec = gobCodec{}
+
+type gobCodec struct{}
+
+func (gobCodec) NewEncoder(w io.Writer) Encoder {
+	return &gobEncoder{encoder: gob.NewEncoder(w)}
+}
+
+func (gobCodec) NewDecoder(r io.Reader) Decoder {
+	return &gobDecoder{decoder: gob.NewDecoder(r)}
+}
+
+type gobEncoder struct {
+	encoder *gob.Encoder
+}
+
+func (e *gobEncoder) Encode(value any) error {
+	return e.encoder.Encode(&value)
+}
+
+type gobDecoder struct {
+	decoder *gob.Decoder
+}
+
+func (d *gobDecoder) Decode() (any, error) {
+	var value any
+
+	if err := d.decoder.Decode(&value); err != nil {
+		return nil, err
+	}
+
+	return value, nil
+}
@@ -0,0 +1,133 @@
+package codec
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/gob"
+	"testing"
+
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+func TestNewTransmitterJSONRoundTrip(t *testing.T) {
+	var buf bytes.Buffer
+
+	transmitter := NewTransmitter(&buf, JSONCodec)
+
+	if err := transmitter.Emit(map[string]any{"hello": "world"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	got, err := transmitter.Receive()
+
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	value, ok := got.(map[string]any)
+
+	if !ok || value["hello"] != "world" {
+		t.Fatalf("expected map[hello:world], got %v", got)
+	}
+}
+
+type gobValue struct {
+	Name string
+}
+
+func TestNewTransmitterGobRoundTrip(t *testing.T) {
+	gob.Register(gobValue{})
+
+	var buf bytes.Buffer
+
+	transmitter := NewTransmitter(&buf, GobCodec)
+
+	if err := transmitter.Emit(gobValue{Name: "alice"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	got, err := transmitter.Receive()
+
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	value, ok := got.(gobValue)
+
+	if !ok || value.Name != "alice" {
+		t.Fatalf("expected {alice}, got %v", got)
+	}
+}
+
+func TestLengthPrefixedCodecRoundTrip(t *testing.T) {
+	var buf bytes.Buffer
+
+	transmitter := NewTransmitter(&buf, LengthPrefixedCodec(binary.BigEndian, 0))
+
+	if err := transmitter.Emit([]byte("hello")); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	got, err := transmitter.Receive()
+
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if string(got.([]byte)) != "hello" {
+		t.Fatalf("expected %q, got %q", "hello", got)
+	}
+}
+
+func TestLengthPrefixedCodecRejectsOversizedFrames(t *testing.T) {
+	var buf bytes.Buffer
+
+	codec := LengthPrefixedCodec(binary.BigEndian, 4)
+
+	if err := codec.NewEncoder(&buf).Encode([]byte("hello")); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if _, err := codec.NewDecoder(&buf).Decode(); err == nil {
+		t.Fatal("expected an error, got nil")
+	}
+}
+
+func TestProtobufCodecRoundTrip(t *testing.T) {
+	var buf bytes.Buffer
+
+	codec := ProtobufCodec(func() proto.Message { return &wrapperspb.StringValue{} }, 0)
+
+	transmitter := NewTransmitter(&buf, codec)
+
+	if err := transmitter.Emit(wrapperspb.String("hello")); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	got, err := transmitter.Receive()
+
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	value, ok := got.(*wrapperspb.StringValue)
+
+	if !ok || value.GetValue() != "hello" {
+		t.Fatalf("expected %q, got %v", "hello", got)
+	}
+}
+
+func TestProtobufCodecRejectsOversizedMessages(t *testing.T) {
+	var buf bytes.Buffer
+
+	codec := ProtobufCodec(func() proto.Message { return &wrapperspb.StringValue{} }, 2)
+
+	if err := codec.NewEncoder(&buf).Encode(wrapperspb.String("hello")); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if _, err := codec.NewDecoder(&buf).Decode(); err == nil {
+		t.Fatal("expected an error, got nil")
+	}
+}
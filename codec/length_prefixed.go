@@ -0,0 +1,81 @@
+package codec
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io"
+)
+
+// LengthPrefixedCodec returns a Codec that frames arbitrary []byte payloads
+// with a 4-byte length prefix encoded using byteOrder. Decode rejects any
+// frame whose declared length exceeds maxSize, to protect against a
+// misbehaving or malicious peer forcing an unbounded allocation. A maxSize
+// of 0 means no limit is enforced.
+func LengthPrefixedCodec(byteOrder binary.ByteOrder, maxSize int) Codec {
+	return &lengthPrefixedCodec{byteOrder: byteOrder, maxSize: maxSize}
+}
+
+type lengthPrefixedCodec struct {
+	byteOrder binary.ByteOrder
+	maxSize   int
+}
+
+func (c *lengthPrefixedCodec) NewEncoder(w io.Writer) Encoder {
+	return &lengthPrefixedEncoder{w: w, byteOrder: c.byteOrder}
+}
+
+func (c *lengthPrefixedCodec) NewDecoder(r io.Reader) Decoder {
+	return &lengthPrefixedDecoder{r: r, byteOrder: c.byteOrder, maxSize: c.maxSize}
+}
+
+type lengthPrefixedEncoder struct {
+	w         io.Writer
+	byteOrder binary.ByteOrder
+}
+
+func (e *lengthPrefixedEncoder) Encode(value any) error {
+	data, ok := value.([]byte)
+
+	if !ok {
+		return fmt.Errorf("codec: expected a []byte, got %T", value)
+	}
+
+	var length [4]byte
+	e.byteOrder.PutUint32(length[:], uint32(len(data)))
+
+	if _, err := e.w.Write(length[:]); err != nil {
+		return err
+	}
+
+	_, err := e.w.Write(data)
+
+	return err
+}
+
+type lengthPrefixedDecoder struct {
+	r         io.Reader
+	byteOrder binary.ByteOrder
+	maxSize   int
+}
+
+func (d *lengthPrefixedDecoder) Decode() (any, error) {
+	var length [4]byte
+
+	if _, err := io.ReadFull(d.r, length[:]); err != nil {
+		return nil, err
+	}
+
+	size := d.byteOrder.Uint32(length[:])
+
+	if d.maxSize > 0 && int(size) > d.maxSize {
+		return nil, fmt.Errorf("codec: frame of %d bytes exceeds the maximum allowed size of %d", size, d.maxSize)
+	}
+
+	data := make([]byte, size)
+
+	if _, err := io.ReadFull(d.r, data); err != nil {
+		return nil, err
+	}
+
+	return data, nil
+}
@@ -0,0 +1,111 @@
+package codec
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io"
+
+	"google.golang.org/protobuf/proto"
+)
+
+// ProtobufCodec returns a Codec that encodes and decodes values as
+// length-prefixed protobuf messages. newMessage is called by every Decode
+// to allocate the message to decode into, since Decode must allocate
+// a concrete proto.Message and cannot infer its type from the stream alone.
+//
+// Decode rejects any message whose declared length exceeds maxSize, to
+// protect against a misbehaving or malicious peer forcing an unbounded
+// allocation. A maxSize of 0 means no limit is enforced.
+func ProtobufCodec(newMessage func() proto.Message, maxSize int) Codec {
+	return &protobufCodec{newMessage: newMessage, maxSize: maxSize}
+}
+
+type protobufCodec struct {
+	newMessage func() proto.Message
+	maxSize    int
+}
+
+func (c *protobufCodec) NewEncoder(w io.Writer) Encoder {
+	return &protobufEncoder{w: w}
+}
+
+func (c *protobufCodec) NewDecoder(r io.Reader) Decoder {
+	return &protobufDecoder{r: r, newMessage: c.newMessage, maxSize: c.maxSize}
+}
+
+type protobufEncoder struct {
+	w io.Writer
+}
+
+func (e *protobufEncoder) Encode(value any) error {
+	message, ok := value.(proto.Message)
+
+	if !ok {
+		return fmt.Errorf("codec: expected a proto.Message, got %T", value)
+	}
+
+	data, err := proto.Marshal(message)
+
+	if err != nil {
+		return err
+	}
+
+	var length [binary.MaxVarintLen64]byte
+	n := binary.PutUvarint(length[:], uint64(len(data)))
+
+	if _, err := e.w.Write(length[:n]); err != nil {
+		return err
+	}
+
+	_, err = e.w.Write(data)
+
+	return err
+}
+
+type protobufDecoder struct {
+	r          io.Reader
+	newMessage func() proto.Message
+	maxSize    int
+}
+
+func (d *protobufDecoder) Decode() (any, error) {
+	size, err := binary.ReadUvarint(byteReader{d.r})
+
+	if err != nil {
+		return nil, err
+	}
+
+	if d.maxSize > 0 && size > uint64(d.maxSize) {
+		return nil, fmt.Errorf("codec: message of %d bytes exceeds the maximum allowed size of %d", size, d.maxSize)
+	}
+
+	data := make([]byte, size)
+
+	if _, err := io.ReadFull(d.r, data); err != nil {
+		return nil, err
+	}
+
+	message := d.newMessage()
+
+	if err := proto.Unmarshal(data, message); err != nil {
+		return nil, err
+	}
+
+	return message, nil
+}
+
+// byteReader adapts an io.Reader to the io.ByteReader interface required by
+// binary.ReadUvarint, reading one byte at a time.
+type byteReader struct {
+	io.Reader
+}
+
+func (r byteReader) ReadByte() (byte, error) {
+	var b [1]byte
+
+	if _, err := io.ReadFull(r.Reader, b[:]); err != nil {
+		return 0, err
+	}
+
+	return b[0], nil
+}
@@ -0,0 +1,247 @@
+package channelio
+
+import (
+	"context"
+	"io"
+	"sync"
+)
+
+// Merge returns a Receiver that concurrently drains every specified receiver
+// and yields their values as they come in, in no particular order.
+//
+// Once every receiver has returned io.EOF, Merge itself returns io.EOF. Any
+// other error returned by one of the receivers is returned immediately,
+// without waiting for the others.
+//
+// Merge honors ctx: once it expires, Receive returns the context error and
+// every goroutine started to drain the underlying receivers stops.
+func Merge(ctx context.Context, receivers ...Receiver) Receiver {
+	workCtx, cancel := context.WithCancel(ctx)
+
+	values := make(chan any)
+	errs := make(chan error, 1)
+
+	var wg sync.WaitGroup
+	wg.Add(len(receivers))
+
+	for _, r := range receivers {
+		go func(r Receiver) {
+			defer wg.Done()
+
+			for {
+				value, err := r.Receive()
+
+				if err != nil {
+					if err != io.EOF {
+						select {
+						case errs <- err:
+							cancel()
+						default:
+						}
+					}
+
+					return
+				}
+
+				select {
+				case values <- value:
+				case <-workCtx.Done():
+					return
+				}
+			}
+		}(r)
+	}
+
+	go func() {
+		wg.Wait()
+
+		select {
+		case errs <- io.EOF:
+		default:
+		}
+
+		cancel()
+	}()
+
+	// Receive watches the caller's own ctx for cancellation, not workCtx:
+	// workCtx is also cancelled once every receiver has been drained, and
+	// that purely internal signal must not race with, and occasionally win
+	// over, a value already queued on errs.
+	return &mergeReceiver{
+		ctx:    ctx,
+		values: values,
+		errs:   errs,
+	}
+}
+
+type mergeReceiver struct {
+	ctx    context.Context
+	values chan any
+	errs   chan error
+}
+
+func (m *mergeReceiver) Receive() (any, error) {
+	select {
+	case value := <-m.values:
+		return value, nil
+	case err := <-m.errs:
+		return nil, err
+	case <-m.ctx.Done():
+		return nil, m.ctx.Err()
+	}
+}
+
+// Filter returns a Receiver that only yields the values from r for which
+// pred returns true. Values rejected by pred are silently dropped.
+//
+// Any error returned by r, including io.EOF, is passed through unchanged.
+func Filter(r Receiver, pred func(value any) bool) Receiver {
+	return &filterReceiver{
+		receiver: r,
+		pred:     pred,
+	}
+}
+
+type filterReceiver struct {
+	receiver Receiver
+	pred     func(value any) bool
+}
+
+func (f *filterReceiver) Receive() (any, error) {
+	for {
+		value, err := f.receiver.Receive()
+
+		if err != nil {
+			return nil, err
+		}
+
+		if f.pred(value) {
+			return value, nil
+		}
+	}
+}
+
+// Map returns a Receiver that applies f to every value received from r
+// before yielding it.
+//
+// If f returns an error, Receive returns that error and does not consume
+// any further value. Any error returned by r, including io.EOF, is passed
+// through unchanged.
+func Map(r Receiver, f func(value any) (any, error)) Receiver {
+	return &mapReceiver{
+		receiver: r,
+		f:        f,
+	}
+}
+
+type mapReceiver struct {
+	receiver Receiver
+	f        func(value any) (any, error)
+}
+
+func (m *mapReceiver) Receive() (any, error) {
+	value, err := m.receiver.Receive()
+
+	if err != nil {
+		return nil, err
+	}
+
+	return m.f(value)
+}
+
+// FanOutPolicy controls what FanOut does with a value when a downstream
+// consumer is not ready to receive it.
+type FanOutPolicy int
+
+const (
+	// FanOutBlock makes FanOut wait until every downstream consumer has
+	// accepted the value before reading the next one from the source. Since
+	// values are delivered to the returned Receivers in order, one at a
+	// time, callers must drain all of them concurrently: reading one to
+	// completion while leaving another unread will deadlock FanOut as soon
+	// as it tries to deliver a value to the unread one.
+	FanOutBlock FanOutPolicy = iota
+
+	// FanOutDrop makes FanOut drop the value for whichever downstream
+	// consumers are not immediately ready to receive it.
+	FanOutDrop
+)
+
+// FanOut duplicates every value received from r to n independent Receivers.
+//
+// FanOut honors ctx: once it expires, every returned Receiver starts
+// returning the context error and the goroutine draining r stops.
+func FanOut(ctx context.Context, r Receiver, n int, policy FanOutPolicy) []Receiver {
+	workCtx, cancel := context.WithCancel(ctx)
+
+	outs := make([]chan any, n)
+	errs := make([]chan error, n)
+	receivers := make([]Receiver, n)
+
+	for i := range outs {
+		outs[i] = make(chan any)
+		errs[i] = make(chan error, 1)
+		receivers[i] = &fanOutReceiver{
+			ctx:    ctx,
+			values: outs[i],
+			errs:   errs[i],
+		}
+	}
+
+	go func() {
+		defer cancel()
+
+		for {
+			value, err := r.Receive()
+
+			if err != nil {
+				for _, e := range errs {
+					e <- err
+				}
+
+				return
+			}
+
+			for _, out := range outs {
+				if policy == FanOutDrop {
+					select {
+					case out <- value:
+					default:
+					}
+
+					continue
+				}
+
+				select {
+				case out <- value:
+				case <-workCtx.Done():
+					return
+				}
+			}
+		}
+	}()
+
+	// fanOutReceiver watches the caller's own ctx for cancellation, not
+	// workCtx: workCtx is also cancelled once the draining goroutine returns
+	// (including on its own terminal error), and that purely internal signal
+	// must not race with, and occasionally win over, an error already queued
+	// on errs.
+	return receivers
+}
+
+type fanOutReceiver struct {
+	ctx    context.Context
+	values chan any
+	errs   chan error
+}
+
+func (f *fanOutReceiver) Receive() (any, error) {
+	select {
+	case value := <-f.values:
+		return value, nil
+	case err := <-f.errs:
+		return nil, err
+	case <-f.ctx.Done():
+		return nil, f.ctx.Err()
+	}
+}
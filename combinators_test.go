@@ -0,0 +1,227 @@
+package channelio
+
+import (
+	"context"
+	"errors"
+	"io"
+	"runtime"
+	"sync"
+	"testing"
+	"time"
+)
+
+// sliceReceiver is a Receiver that yields the values of a slice, then
+// returns io.EOF forever.
+type sliceReceiver struct {
+	values []interface{}
+}
+
+func (r *sliceReceiver) Receive() (interface{}, error) {
+	if len(r.values) == 0 {
+		return nil, io.EOF
+	}
+
+	value := r.values[0]
+	r.values = r.values[1:]
+
+	return value, nil
+}
+
+// blockingReceiver is a Receiver whose Receive call never returns on its
+// own; it only unblocks when ctx expires, as RunReceiver's contract
+// requires of any Receiver used with it.
+type blockingReceiver struct {
+	ctx context.Context
+}
+
+func (r *blockingReceiver) Receive() (interface{}, error) {
+	<-r.ctx.Done()
+
+	return nil, r.ctx.Err()
+}
+
+func waitForGoroutines(t *testing.T, before int) {
+	t.Helper()
+
+	deadline := time.Now().Add(time.Second)
+
+	for time.Now().Before(deadline) {
+		if runtime.NumGoroutine() <= before {
+			return
+		}
+
+		time.Sleep(time.Millisecond)
+	}
+
+	t.Fatalf("goroutines leaked: started with %d, still have %d", before, runtime.NumGoroutine())
+}
+
+func TestMergeYieldsAllValuesThenEOF(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	r1 := &sliceReceiver{values: []interface{}{1, 2}}
+	r2 := &sliceReceiver{values: []interface{}{3}}
+
+	merged := Merge(ctx, r1, r2)
+
+	seen := map[interface{}]bool{}
+
+	for i := 0; i < 3; i++ {
+		value, err := merged.Receive()
+
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		seen[value] = true
+	}
+
+	for _, value := range []interface{}{1, 2, 3} {
+		if !seen[value] {
+			t.Fatalf("expected to have seen %v, got %v", value, seen)
+		}
+	}
+
+	if _, err := merged.Receive(); err != io.EOF {
+		t.Fatalf("expected io.EOF once exhausted, got %v", err)
+	}
+}
+
+func TestMergePropagatesNonEOFError(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	failure := errors.New("boom")
+
+	merged := Merge(ctx, &sliceReceiver{values: []interface{}{1}}, &failingReceiver{err: failure})
+
+	var gotFailure bool
+
+	for i := 0; i < 2; i++ {
+		_, err := merged.Receive()
+
+		if err == failure {
+			gotFailure = true
+		}
+	}
+
+	if !gotFailure {
+		t.Fatalf("expected the underlying failure to be propagated")
+	}
+}
+
+type failingReceiver struct {
+	err error
+}
+
+func (r *failingReceiver) Receive() (interface{}, error) {
+	return nil, r.err
+}
+
+func TestMergeCancellationStopsGoroutines(t *testing.T) {
+	before := runtime.NumGoroutine()
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	merged := Merge(ctx, &blockingReceiver{ctx: ctx}, &blockingReceiver{ctx: ctx})
+
+	cancel()
+
+	if _, err := merged.Receive(); err != context.Canceled {
+		t.Fatalf("expected context.Canceled, got %v", err)
+	}
+
+	waitForGoroutines(t, before)
+}
+
+func TestFanOutDuplicatesValuesToEveryReceiver(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	source := &sliceReceiver{values: []interface{}{1, 2}}
+
+	receivers := FanOut(ctx, source, 3, FanOutBlock)
+
+	// FanOutBlock only reads the next value from source once every receiver
+	// has accepted the current one, so the receivers must be drained
+	// concurrently: draining one to completion before touching the next
+	// would deadlock as soon as FanOut tries to deliver a value to one of the
+	// undrained receivers.
+	var wg sync.WaitGroup
+	wg.Add(len(receivers))
+
+	for _, r := range receivers {
+		go func(r Receiver) {
+			defer wg.Done()
+
+			for _, want := range []interface{}{1, 2} {
+				got, err := r.Receive()
+
+				if err != nil {
+					t.Errorf("unexpected error: %v", err)
+					return
+				}
+
+				if got != want {
+					t.Errorf("expected %v, got %v", want, got)
+					return
+				}
+			}
+		}(r)
+	}
+
+	wg.Wait()
+}
+
+func TestFanOutYieldsEOFReliably(t *testing.T) {
+	// Regression test for a race where the draining goroutine's internal
+	// cleanup context (now workCtx) was the same context every
+	// fanOutReceiver watched: cancelling it right after queuing io.EOF raced
+	// the receivers' own select, occasionally yielding context.Canceled
+	// instead of the queued io.EOF.
+	for i := 0; i < 200; i++ {
+		ctx, cancel := context.WithCancel(context.Background())
+
+		receivers := FanOut(ctx, &sliceReceiver{values: []interface{}{1}}, 2, FanOutBlock)
+
+		var wg sync.WaitGroup
+		wg.Add(len(receivers))
+
+		for _, r := range receivers {
+			go func(r Receiver) {
+				defer wg.Done()
+
+				if _, err := r.Receive(); err != nil {
+					t.Errorf("unexpected error: %v", err)
+					return
+				}
+
+				if _, err := r.Receive(); err != io.EOF {
+					t.Errorf("expected io.EOF, got %v", err)
+				}
+			}(r)
+		}
+
+		wg.Wait()
+		cancel()
+	}
+}
+
+func TestFanOutCancellationStopsGoroutineAndReceivers(t *testing.T) {
+	before := runtime.NumGoroutine()
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	receivers := FanOut(ctx, &blockingReceiver{ctx: ctx}, 2, FanOutBlock)
+
+	cancel()
+
+	for _, r := range receivers {
+		if _, err := r.Receive(); err != context.Canceled {
+			t.Fatalf("expected context.Canceled, got %v", err)
+		}
+	}
+
+	waitForGoroutines(t, before)
+}
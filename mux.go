@@ -0,0 +1,281 @@
+package channelio
+
+import (
+	"context"
+	"fmt"
+	"sync"
+)
+
+// Envelope pairs a Message with the ChannelID of the logical stream it
+// belongs to, so that several typed streams can share a single Transmitter.
+type Envelope struct {
+	ChannelID uint16
+	Message   any
+}
+
+// Wrapper packages a value into something that can travel as an Envelope's
+// Message, and back. It is the extension point for customizing how a
+// sub-channel's values are packaged on the wire (for instance for signing or
+// encryption) without Mux having to know about it. Envelope itself satisfies
+// Wrapper by storing the value as-is.
+type Wrapper interface {
+	// Wrap packages value, making it retrievable through Unwrap.
+	Wrap(value any) error
+	// Unwrap returns the value previously packaged by Wrap.
+	Unwrap() (any, error)
+}
+
+// Wrap stores value as the Envelope's Message.
+func (e *Envelope) Wrap(value any) error {
+	e.Message = value
+	return nil
+}
+
+// Unwrap returns the Envelope's Message.
+func (e *Envelope) Unwrap() (any, error) {
+	return e.Message, nil
+}
+
+// Mux multiplexes several logical Transmitters over a single underlying
+// Transmitter, routing messages by ChannelID. This lets one connection
+// carry many typed streams without callers hand-rolling a discriminator.
+//
+// The underlying Transmitter is expected to carry Envelope values, whether
+// as a concrete Envelope or as the generic shape a codec like
+// codec.JSONCodec decodes one into (see decodeEnvelope); any other value it
+// emits is dropped.
+type Mux struct {
+	transmitter Transmitter
+	newWrapper  func() Wrapper
+
+	mu       sync.Mutex
+	channels map[uint16]chan any
+	ctx      context.Context
+	outbound chan any
+}
+
+// NewMux creates a Mux that multiplexes the Transmitters returned by Channel
+// over t. Every value emitted on one of those Transmitters is packaged
+// through a fresh Wrapper obtained from newWrapper before being sent as an
+// Envelope's Message. This is the hook through which callers can
+// transparently sign or encrypt outbound sub-channel traffic without Mux
+// having to know about it. newWrapper is not used on the receiving side: a
+// received Envelope's Message is unwrapped only if it is itself a Wrapper
+// value, and is delivered as-is otherwise (see dispatch). Pass
+// func() Wrapper { return &Envelope{} } to send messages unmodified.
+func NewMux(t Transmitter, newWrapper func() Wrapper) *Mux {
+	return &Mux{
+		transmitter: t,
+		newWrapper:  newWrapper,
+		channels:    make(map[uint16]chan any),
+	}
+}
+
+// Channel returns the virtual Transmitter for the specified channel ID. It
+// only sees messages tagged with that ID, and tags every message it emits
+// with it.
+//
+// Run must be running for the returned Transmitter to make progress.
+func (m *Mux) Channel(id uint16) Transmitter {
+	return &muxTransmitter{
+		mux:     m,
+		id:      id,
+		inbound: m.channel(id),
+	}
+}
+
+func (m *Mux) channel(id uint16) chan any {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	ch, ok := m.channels[id]
+
+	if !ok {
+		ch = make(chan any)
+		m.channels[id] = ch
+	}
+
+	return ch
+}
+
+// Run runs a single RunTransmitter against the underlying Transmitter,
+// dispatching every received Envelope to the channel returned by Channel for
+// its ChannelID, and carrying every value emitted by such a channel as an
+// Envelope tagged with its ID.
+//
+// The underlying Transmitter is free to decode Envelope values however it
+// sees fit: a concrete Envelope (as a plain in-memory Transmitter or a gob
+// Transmitter with Envelope registered would produce) is used as-is, while a
+// generic map[string]any (as a codec.JSONCodec-backed Transmitter would
+// produce) is converted first. Any other shape is dropped.
+//
+// The call only returns once ctx expires or the underlying Transmitter
+// fails, and must not be called more than once.
+func (m *Mux) Run(ctx context.Context) error {
+	outbound := make(chan any)
+	inbound := make(chan any)
+
+	m.mu.Lock()
+	m.ctx = ctx
+	m.outbound = outbound
+	m.mu.Unlock()
+
+	result := make(chan error, 1)
+
+	go func() {
+		result <- RunTransmitter(ctx, m.transmitter, outbound, inbound)
+	}()
+
+	for {
+		select {
+		case value, ok := <-inbound:
+			if !ok {
+				inbound = nil
+				break
+			}
+
+			envelope, ok := decodeEnvelope(value)
+
+			if !ok {
+				continue
+			}
+
+			m.dispatch(ctx, envelope)
+		case err := <-result:
+			return err
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+// decodeEnvelope converts value, as received from the underlying
+// Transmitter, into an Envelope. It accepts a concrete Envelope or *Envelope
+// unchanged, and also recognizes the map[string]any shape produced by
+// decoding a JSON-encoded Envelope into an empty interface.
+func decodeEnvelope(value any) (Envelope, bool) {
+	switch v := value.(type) {
+	case Envelope:
+		return v, true
+	case *Envelope:
+		return *v, true
+	case map[string]any:
+		channelID, ok := decodeChannelID(v["ChannelID"])
+
+		if !ok {
+			return Envelope{}, false
+		}
+
+		return Envelope{ChannelID: channelID, Message: v["Message"]}, true
+	default:
+		return Envelope{}, false
+	}
+}
+
+// decodeChannelID converts value, as decoded for an Envelope's ChannelID
+// field, into a uint16. JSON decodes numbers as float64, so that is the main
+// shape handled here in addition to the native type.
+func decodeChannelID(value any) (uint16, bool) {
+	switch v := value.(type) {
+	case uint16:
+		return v, true
+	case float64:
+		return uint16(v), true
+	default:
+		return 0, false
+	}
+}
+
+// dispatch delivers envelope's Message to the channel registered for its
+// ChannelID, if any. If Message itself packages the value through a Wrapper
+// (the case for a custom newWrapper that doesn't fold into the outer
+// Envelope), it is unwrapped first. Envelopes tagged with an ID nobody called
+// Channel for, and envelopes whose Message fails to unwrap, are dropped.
+func (m *Mux) dispatch(ctx context.Context, envelope Envelope) {
+	m.mu.Lock()
+	ch, ok := m.channels[envelope.ChannelID]
+	m.mu.Unlock()
+
+	if !ok {
+		return
+	}
+
+	value := envelope.Message
+
+	if wrapper, ok := value.(Wrapper); ok {
+		var err error
+
+		value, err = wrapper.Unwrap()
+
+		if err != nil {
+			return
+		}
+	}
+
+	select {
+	case ch <- value:
+	case <-ctx.Done():
+	}
+}
+
+type muxTransmitter struct {
+	mux     *Mux
+	id      uint16
+	inbound chan any
+}
+
+func (t *muxTransmitter) Emit(value any) error {
+	t.mux.mu.Lock()
+	ctx := t.mux.ctx
+	outbound := t.mux.outbound
+	t.mux.mu.Unlock()
+
+	if ctx == nil {
+		return fmt.Errorf("channelio: mux is not running")
+	}
+
+	wrapper := t.mux.newWrapper()
+
+	if err := wrapper.Wrap(value); err != nil {
+		return err
+	}
+
+	// If the configured Wrapper is itself an *Envelope (NewMux's documented
+	// default, func() Wrapper { return &Envelope{} }), it already carries a
+	// Message; folding it into the outer Envelope's Message would nest an
+	// Envelope inside another one instead of sending value directly. Give it
+	// the ChannelID and send it as the outer Envelope instead. A Wrapper of
+	// any other type has no ChannelID field of its own, so it is boxed as the
+	// outer Envelope's Message as before.
+	envelope, ok := wrapper.(*Envelope)
+
+	if ok {
+		envelope.ChannelID = t.id
+	} else {
+		envelope = &Envelope{ChannelID: t.id, Message: wrapper}
+	}
+
+	select {
+	case outbound <- *envelope:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (t *muxTransmitter) Receive() (any, error) {
+	t.mux.mu.Lock()
+	ctx := t.mux.ctx
+	t.mux.mu.Unlock()
+
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	select {
+	case value := <-t.inbound:
+		return value, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
@@ -0,0 +1,103 @@
+package channelio_test
+
+import (
+	"context"
+	"io"
+	"testing"
+	"time"
+
+	channelio "github.com/ereOn/go-channelio"
+	"github.com/ereOn/go-channelio/codec"
+)
+
+// pipeConn turns a pair of io.Pipe halves into a single io.ReadWriter,
+// standing in for a real duplex connection such as a net.Conn.
+type pipeConn struct {
+	r *io.PipeReader
+	w *io.PipeWriter
+}
+
+func (c pipeConn) Read(p []byte) (int, error) {
+	return c.r.Read(p)
+}
+
+func (c pipeConn) Write(p []byte) (int, error) {
+	return c.w.Write(p)
+}
+
+// TestMuxOverJSONCodecRoundTrip pipes a Mux through a real codec.Transmitter
+// backed by an io.Pipe, instead of the synthetic fake Transmitter used by the
+// internal Mux tests. A codec.JSONCodec-backed Transmitter decodes Envelope
+// values into a generic map[string]any rather than a concrete Envelope, so
+// this exercises the decoding path a real (de)serializing Transmitter takes.
+func TestMuxOverJSONCodecRoundTrip(t *testing.T) {
+	clientToServerR, clientToServerW := io.Pipe()
+	serverToClientR, serverToClientW := io.Pipe()
+
+	client := codec.NewTransmitter(pipeConn{r: serverToClientR, w: clientToServerW}, codec.JSONCodec)
+	server := codec.NewTransmitter(pipeConn{r: clientToServerR, w: serverToClientW}, codec.JSONCodec)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	mux := channelio.NewMux(server, func() channelio.Wrapper { return &channelio.Envelope{} })
+
+	go mux.Run(ctx)
+
+	channel := mux.Channel(5)
+
+	go func() {
+		if err := client.Emit(channelio.Envelope{ChannelID: 5, Message: "hello"}); err != nil {
+			t.Errorf("unexpected error: %v", err)
+		}
+	}()
+
+	received := make(chan any, 1)
+	errs := make(chan error, 1)
+
+	go func() {
+		value, err := channel.Receive()
+
+		if err != nil {
+			errs <- err
+			return
+		}
+
+		received <- value
+	}()
+
+	select {
+	case got := <-received:
+		if got != "hello" {
+			t.Fatalf("expected %q, got %v", "hello", got)
+		}
+	case err := <-errs:
+		t.Fatalf("unexpected error: %v", err)
+	case <-time.After(time.Second):
+		t.Fatal("timed out waiting for the dispatched value")
+	}
+
+	if err := channel.Emit("world"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	got, err := client.Receive()
+
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	envelope, ok := got.(map[string]any)
+
+	if !ok {
+		t.Fatalf("expected a map[string]any, got %T", got)
+	}
+
+	if envelope["ChannelID"] != float64(5) {
+		t.Fatalf("expected channel ID 5, got %v", envelope["ChannelID"])
+	}
+
+	if envelope["Message"] != "world" {
+		t.Fatalf("expected %q, got %v", "world", envelope["Message"])
+	}
+}
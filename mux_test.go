@@ -0,0 +1,200 @@
+package channelio
+
+import (
+	"context"
+	"io"
+	"testing"
+	"time"
+)
+
+// pipeTransmitter is a Transmitter backed by two channels, letting a test
+// stand in for the remote peer of whatever it is wired to.
+type pipeTransmitter struct {
+	in  chan any
+	out chan any
+}
+
+func newPipeTransmitter() *pipeTransmitter {
+	return &pipeTransmitter{
+		in:  make(chan any),
+		out: make(chan any),
+	}
+}
+
+func (p *pipeTransmitter) Emit(value any) error {
+	p.out <- value
+
+	return nil
+}
+
+func (p *pipeTransmitter) Receive() (any, error) {
+	value, ok := <-p.in
+
+	if !ok {
+		return nil, io.EOF
+	}
+
+	return value, nil
+}
+
+// identityWrapper is the func() Wrapper used by tests that don't care about
+// packaging; it mirrors NewMux's documented default.
+func identityWrapper() Wrapper {
+	return &Envelope{}
+}
+
+// waitUntilRunning blocks until m.Run has started handling ctx, so that
+// tests don't race Emit/Receive against Run's setup.
+func waitUntilRunning(t *testing.T, m *Mux) {
+	t.Helper()
+
+	deadline := time.Now().Add(time.Second)
+
+	for time.Now().Before(deadline) {
+		m.mu.Lock()
+		running := m.ctx != nil
+		m.mu.Unlock()
+
+		if running {
+			return
+		}
+
+		time.Sleep(time.Millisecond)
+	}
+
+	t.Fatal("timed out waiting for the Mux to start running")
+}
+
+func TestMuxTagsEmittedValuesWithChannelID(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	pipe := newPipeTransmitter()
+	mux := NewMux(pipe, identityWrapper)
+
+	go mux.Run(ctx)
+	waitUntilRunning(t, mux)
+
+	channel := mux.Channel(7)
+
+	go func() {
+		if err := channel.Emit("hello"); err != nil {
+			t.Errorf("unexpected error: %v", err)
+		}
+	}()
+
+	select {
+	case value := <-pipe.out:
+		envelope, ok := value.(Envelope)
+
+		if !ok {
+			t.Fatalf("expected an Envelope, got %T", value)
+		}
+
+		if envelope.ChannelID != 7 {
+			t.Fatalf("expected channel ID 7, got %d", envelope.ChannelID)
+		}
+
+		// With the default identity Wrapper, Message carries value directly:
+		// folding it into a Wrapper and boxing that Wrapper as Message again
+		// would nest an Envelope inside another one on the wire.
+		if envelope.Message != "hello" {
+			t.Fatalf("expected %q, got %v", "hello", envelope.Message)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("timed out waiting for the emitted envelope")
+	}
+}
+
+func TestMuxDispatchesReceivedEnvelopesByChannelID(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	pipe := newPipeTransmitter()
+	mux := NewMux(pipe, identityWrapper)
+
+	go mux.Run(ctx)
+
+	channelA := mux.Channel(1)
+	channelB := mux.Channel(2)
+
+	wrapper := identityWrapper()
+
+	if err := wrapper.Wrap("for-b"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	go func() {
+		pipe.in <- Envelope{ChannelID: 2, Message: wrapper}
+	}()
+
+	got, err := channelB.Receive()
+
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if got != "for-b" {
+		t.Fatalf("expected %q, got %v", "for-b", got)
+	}
+
+	select {
+	case _, ok := <-channelA.(*muxTransmitter).inbound:
+		if ok {
+			t.Fatal("did not expect a value on the unrelated channel")
+		}
+	default:
+	}
+}
+
+func TestMuxDropsEnvelopesForUnregisteredChannelID(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	pipe := newPipeTransmitter()
+	mux := NewMux(pipe, identityWrapper)
+
+	go mux.Run(ctx)
+	waitUntilRunning(t, mux)
+
+	registered := mux.Channel(1)
+
+	orphan := identityWrapper()
+
+	if err := orphan.Wrap("orphan"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	known := identityWrapper()
+
+	if err := known.Wrap("known"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// Nobody called Channel(42), so dispatching an envelope tagged with it
+	// must be a silent no-op rather than a block or a panic: the next
+	// envelope, for a channel that was registered, must still get through.
+	go func() {
+		pipe.in <- Envelope{ChannelID: 42, Message: orphan}
+		pipe.in <- Envelope{ChannelID: 1, Message: known}
+	}()
+
+	got, err := registered.Receive()
+
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if got != "known" {
+		t.Fatalf("expected %q, got %v", "known", got)
+	}
+}
+
+func TestMuxEmitBeforeRunFails(t *testing.T) {
+	pipe := newPipeTransmitter()
+	mux := NewMux(pipe, identityWrapper)
+
+	if err := mux.Channel(1).Emit("too early"); err == nil {
+		t.Fatal("expected an error, got nil")
+	}
+}
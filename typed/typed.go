@@ -0,0 +1,223 @@
+// Package typed mirrors the channelio package's Emitter/Receiver/Transmitter
+// API, but operates on typed channels instead of channels of interface{}.
+//
+// It lets callers work with channelio the way the Go standard library's
+// proposed "chans" package lets callers work with chan Elem: without
+// type-asserting every value that comes off a channel. The untyped
+// channelio package is unaffected and remains the supported
+// backwards-compatible surface for existing callers.
+//
+// RunEmitter, RunReceiver and RunTransmitter are thin, typed wrappers around
+// their channelio counterparts: they bridge the typed channels to channels
+// of any and delegate all of the actual cancellation and cleanup logic to
+// channelio, so the two packages can't silently drift apart.
+package typed
+
+import (
+	"context"
+
+	"github.com/ereOn/go-channelio"
+)
+
+// Emitter represents a type that is able to encode a given value of type T.
+type Emitter[T any] interface {
+	// Emit a value.
+	Emit(value T) error
+}
+
+// Receiver represents a type that is able to decode a given value of type T.
+type Receiver[T any] interface {
+	// Receive a value.
+	Receive() (T, error)
+}
+
+// Transmitter represents a type that acts both as a Receiver and an Emitter.
+type Transmitter[T any] interface {
+	Emitter[T]
+	Receiver[T]
+}
+
+// emitterAdapter adapts an Emitter[T] to the untyped channelio.Emitter.
+type emitterAdapter[T any] struct {
+	emitter Emitter[T]
+}
+
+func (e emitterAdapter[T]) Emit(value any) error {
+	return e.emitter.Emit(value.(T))
+}
+
+// receiverAdapter adapts a Receiver[T] to the untyped channelio.Receiver.
+type receiverAdapter[T any] struct {
+	receiver Receiver[T]
+}
+
+func (r receiverAdapter[T]) Receive() (any, error) {
+	return r.receiver.Receive()
+}
+
+// transmitterAdapter adapts a Transmitter[T] to the untyped
+// channelio.Transmitter.
+type transmitterAdapter[T any] struct {
+	emitterAdapter[T]
+	receiverAdapter[T]
+}
+
+// forwardValues copies values from typed to untyped until either typed is
+// closed, in which case untyped is closed in turn, or ctx expires.
+func forwardValues[T any](ctx context.Context, typed <-chan T, untyped chan<- any) {
+	defer close(untyped)
+
+	for {
+		select {
+		case value, ok := <-typed:
+			if !ok {
+				return
+			}
+
+			select {
+			case untyped <- value:
+			case <-ctx.Done():
+				return
+			}
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// RunEmitter reads all the values from the specified channel and pushes
+// them through the specified Emitter.
+//
+// The call only returns if either:
+// - The specified context expires. In that case the context error is returned.
+// - The emitting process returns an error. In that case, this error is
+// returned.
+//
+// If the values channel is closed, the call will still block until the
+// specified context expires. To control the lifetime of the call, the caller
+// must control the expiration of the context.
+//
+// The caller may close the channel to indicate that no more values are to be
+// emitted. Note that even in that case, the call will still block until the
+// specified context expires.
+func RunEmitter[T any](ctx context.Context, emitter Emitter[T], values <-chan T) error {
+	untyped := make(chan any)
+
+	go forwardValues(ctx, values, untyped)
+
+	return channelio.RunEmitter(ctx, emitterAdapter[T]{emitter: emitter}, untyped)
+}
+
+// RunReceiver continuously reads values from the specified Receiver and pushes
+// them to the specified channel.
+//
+// The call only returns if either:
+// - The specified context expires. In that case the context error is returned.
+// - The receiving process returns an error. In that case, this error is
+// returned.
+//
+// The call takes ownership of the specified channel and will close it.
+//
+// It is the caller's responsibility to ensure that the specified receiver
+// unblocks immediately as the specified context expires.
+func RunReceiver[T any](ctx context.Context, receiver Receiver[T], values chan<- T) error {
+	defer close(values)
+
+	untyped := make(chan any)
+	result := make(chan error, 1)
+
+	go func() {
+		result <- channelio.RunReceiver(ctx, receiverAdapter[T]{receiver: receiver}, untyped)
+	}()
+
+	for {
+		select {
+		case value, ok := <-untyped:
+			if !ok {
+				untyped = nil
+				break
+			}
+
+			select {
+			case values <- value.(T):
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		case err := <-result:
+			return err
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+// RunTransmitter combines the RunEmitter and RunReceiver functions.
+//
+// The call only returns if either:
+// - The specified context expires. In that case the context error is returned.
+// - The emitting process returns an error. In that case, this error is
+// returned.
+// - The receiving process returns an error. In that case, this error is
+// returned.
+//
+// The caller may close the emitter channel to indicate that no more values are
+// to be emitted. Note that even in that case, the call will still block until
+// the specified context expires or the receiving process fails.
+//
+// The call takes ownership of the specified receiver channel and will close
+// it.
+//
+// It is the caller's responsibility to ensure that the specified transmitter
+// unblocks immediately as the specified context expires.
+func RunTransmitter[T any](ctx context.Context, transmitter Transmitter[T], emitterValues <-chan T, receiverValues chan<- T) error {
+	defer close(receiverValues)
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	untypedEmitterValues := make(chan any)
+	untypedReceiverValues := make(chan any)
+
+	go forwardValues(ctx, emitterValues, untypedEmitterValues)
+
+	result := make(chan error, 1)
+
+	go func() {
+		adapter := transmitterAdapter[T]{
+			emitterAdapter:  emitterAdapter[T]{emitter: transmitter},
+			receiverAdapter: receiverAdapter[T]{receiver: transmitter},
+		}
+
+		result <- channelio.RunTransmitter(ctx, adapter, untypedEmitterValues, untypedReceiverValues)
+	}()
+
+	for {
+		select {
+		case value, ok := <-untypedReceiverValues:
+			if !ok {
+				untypedReceiverValues = nil
+				break
+			}
+
+			select {
+			case receiverValues <- value.(T):
+			case <-ctx.Done():
+			}
+		case err := <-result:
+			return err
+		}
+	}
+}
+
+// ComposeTransmitter composes an Emitter and a Receiver into a Transmitter.
+func ComposeTransmitter[T any](emitter Emitter[T], receiver Receiver[T]) Transmitter[T] {
+	return transmitter[T]{
+		Emitter:  emitter,
+		Receiver: receiver,
+	}
+}
+
+type transmitter[T any] struct {
+	Emitter[T]
+	Receiver[T]
+}
@@ -0,0 +1,279 @@
+package typed
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+)
+
+// captureEmitter is an Emitter[int] that records every value it is given.
+type captureEmitter struct {
+	mu     sync.Mutex
+	values []int
+}
+
+func (e *captureEmitter) Emit(value int) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	e.values = append(e.values, value)
+
+	return nil
+}
+
+func (e *captureEmitter) snapshot() []int {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	return append([]int(nil), e.values...)
+}
+
+// waitForCaptured polls e until it has captured exactly want, giving the
+// background goroutine driving it time to call Emit before the test reads
+// its state.
+func waitForCaptured(t *testing.T, e *captureEmitter, want []int) {
+	t.Helper()
+
+	deadline := time.Now().Add(time.Second)
+
+	equal := func(a, b []int) bool {
+		if len(a) != len(b) {
+			return false
+		}
+
+		for i := range a {
+			if a[i] != b[i] {
+				return false
+			}
+		}
+
+		return true
+	}
+
+	for time.Now().Before(deadline) {
+		if equal(e.snapshot(), want) {
+			return
+		}
+
+		time.Sleep(time.Millisecond)
+	}
+
+	t.Fatalf("expected %v, got %v", want, e.snapshot())
+}
+
+// failingEmitter is an Emitter[int] whose Emit always fails with err.
+type failingEmitter struct {
+	err error
+}
+
+func (e *failingEmitter) Emit(value int) error {
+	return e.err
+}
+
+// sliceReceiver is a Receiver[int] that yields the values of a slice, then
+// blocks until ctx expires, as RunReceiver's contract requires of any
+// Receiver used with it.
+type sliceReceiver struct {
+	ctx    context.Context
+	values []int
+}
+
+func (r *sliceReceiver) Receive() (int, error) {
+	if len(r.values) == 0 {
+		<-r.ctx.Done()
+
+		return 0, r.ctx.Err()
+	}
+
+	value := r.values[0]
+	r.values = r.values[1:]
+
+	return value, nil
+}
+
+// failingReceiver is a Receiver[int] whose Receive always fails with err.
+type failingReceiver struct {
+	err error
+}
+
+func (r *failingReceiver) Receive() (int, error) {
+	return 0, r.err
+}
+
+func TestRunEmitterForwardsValuesUntilChannelCloses(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	emitter := &captureEmitter{}
+	values := make(chan int)
+
+	done := make(chan error, 1)
+
+	go func() {
+		done <- RunEmitter(ctx, emitter, values)
+	}()
+
+	values <- 1
+	values <- 2
+	values <- 3
+	close(values)
+
+	select {
+	case err := <-done:
+		t.Fatalf("expected RunEmitter to keep blocking after the channel closes, got %v", err)
+	case <-time.After(10 * time.Millisecond):
+	}
+
+	cancel()
+
+	err := <-done
+
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected context.Canceled, got %v", err)
+	}
+
+	waitForCaptured(t, emitter, []int{1, 2, 3})
+}
+
+func TestRunEmitterPropagatesEmitError(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	wantErr := errors.New("boom")
+	emitter := &failingEmitter{err: wantErr}
+	values := make(chan int, 1)
+	values <- 1
+
+	if err := RunEmitter(ctx, emitter, values); err != wantErr {
+		t.Fatalf("expected %v, got %v", wantErr, err)
+	}
+}
+
+func TestRunReceiverForwardsValuesThenClosesChannel(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	receiver := &sliceReceiver{ctx: ctx, values: []int{1, 2, 3}}
+	values := make(chan int)
+
+	done := make(chan error, 1)
+
+	go func() {
+		done <- RunReceiver(ctx, receiver, values)
+	}()
+
+	for _, want := range []int{1, 2, 3} {
+		if got := <-values; got != want {
+			t.Fatalf("expected %d, got %d", want, got)
+		}
+	}
+
+	cancel()
+
+	if err := <-done; !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected context.Canceled, got %v", err)
+	}
+
+	if _, ok := <-values; ok {
+		t.Fatal("expected the values channel to be closed")
+	}
+}
+
+func TestRunReceiverPropagatesReceiveError(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	wantErr := errors.New("boom")
+	receiver := &failingReceiver{err: wantErr}
+	values := make(chan int)
+
+	if err := RunReceiver(ctx, receiver, values); err != wantErr {
+		t.Fatalf("expected %v, got %v", wantErr, err)
+	}
+}
+
+func TestComposeTransmitterDispatchesToBoth(t *testing.T) {
+	emitter := &captureEmitter{}
+	receiver := &sliceReceiver{ctx: context.Background(), values: []int{42}}
+
+	transmitter := ComposeTransmitter[int](emitter, receiver)
+
+	if err := transmitter.Emit(7); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(emitter.values) != 1 || emitter.values[0] != 7 {
+		t.Fatalf("expected [7], got %v", emitter.values)
+	}
+
+	got, err := transmitter.Receive()
+
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if got != 42 {
+		t.Fatalf("expected 42, got %d", got)
+	}
+}
+
+func TestRunTransmitterForwardsBothDirections(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	emitter := &captureEmitter{}
+	receiver := &sliceReceiver{ctx: ctx, values: []int{1, 2}}
+	transmitter := ComposeTransmitter[int](emitter, receiver)
+
+	emitterValues := make(chan int)
+	receiverValues := make(chan int)
+
+	done := make(chan error, 1)
+
+	go func() {
+		done <- RunTransmitter[int](ctx, transmitter, emitterValues, receiverValues)
+	}()
+
+	emitterValues <- 99
+
+	for _, want := range []int{1, 2} {
+		if got := <-receiverValues; got != want {
+			t.Fatalf("expected %d, got %d", want, got)
+		}
+	}
+
+	// Wait for the emitted value to land before cancelling: once ctx
+	// expires, a value already in flight between the typed and untyped
+	// channels is allowed to be dropped rather than delivered, per
+	// RunEmitter's contract.
+	waitForCaptured(t, emitter, []int{99})
+
+	cancel()
+
+	if err := <-done; !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected context.Canceled, got %v", err)
+	}
+
+	if _, ok := <-receiverValues; ok {
+		t.Fatal("expected the receiver channel to be closed")
+	}
+}
+
+func TestRunTransmitterPropagatesReceiveError(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	wantErr := errors.New("boom")
+	emitter := &captureEmitter{}
+	receiver := &failingReceiver{err: wantErr}
+	transmitter := ComposeTransmitter[int](emitter, receiver)
+
+	emitterValues := make(chan int)
+	receiverValues := make(chan int)
+
+	if err := RunTransmitter[int](ctx, transmitter, emitterValues, receiverValues); err != wantErr {
+		t.Fatalf("expected %v, got %v", wantErr, err)
+	}
+}